@@ -0,0 +1,49 @@
+package session
+
+import (
+	"net"
+	"net/netip"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// hostnameRE matches a single RFC 1123 label or a dot-separated sequence of
+// them; it's deliberately permissive about length limits since the DB
+// column, not this check, is the source of truth for those.
+var hostnameRE = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*$`)
+
+// parseAddress is the pluggable address parser NewConnection uses to
+// validate and normalize ClientAddress/BackendAddress. It's a package
+// variable rather than a hardcoded call so it can be swapped out in tests.
+var parseAddress = parseNetipAddress
+
+// parseNetipAddress accepts an IPv4 or IPv6 literal -- bare ("::1"),
+// bracketed ("[::1]"), with a zone id ("fe80::1%eth0"), or with a port
+// ("[::1]:22", "127.0.0.1:22") -- and normalizes it to its canonical
+// net/netip form, discarding any port (callers track port separately). If
+// allowHostname is true, a string that isn't an IP literal but matches
+// hostnameRE is accepted as a DNS hostname instead of rejected.
+func parseNetipAddress(address string, allowHostname bool) (string, error) {
+	if address == "" {
+		return "", db.ErrInvalidParameter
+	}
+
+	candidate := address
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		candidate = host
+	} else if strings.HasPrefix(address, "[") && strings.HasSuffix(address, "]") {
+		candidate = address[1 : len(address)-1]
+	}
+
+	if addr, err := netip.ParseAddr(candidate); err == nil {
+		return addr.String(), nil
+	}
+
+	if allowHostname && hostnameRE.MatchString(candidate) {
+		return candidate, nil
+	}
+
+	return "", db.ErrInvalidParameter
+}
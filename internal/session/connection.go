@@ -0,0 +1,153 @@
+package session
+
+import (
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+)
+
+const defaultConnectionTableName = "session_connection"
+
+// CloseReason describes why a connection was terminated. It's stored
+// alongside the final byte/packet counters when a connection is closed so
+// operators can distinguish a clean shutdown from a network failure.
+type CloseReason string
+
+const (
+	UnknownReason CloseReason = "unknown"
+	TimedOut      CloseReason = "timed_out"
+	ClientClosed  CloseReason = "client_closed"
+	BackendClosed CloseReason = "backend_closed"
+	Canceled      CloseReason = "canceled"
+	NetworkError  CloseReason = "network_error"
+)
+
+// ConnectionStats holds the counters a worker reports for a connection,
+// either as an in-flight update or as the final tally when the connection
+// is closed.
+type ConnectionStats struct {
+	BytesUp     uint64
+	BytesDown   uint64
+	PacketsUp   uint64
+	PacketsDown uint64
+}
+
+// Connection is a connection made through a session.
+type Connection struct {
+	// PublicId is a surrogate key suitable for use in a public API
+	PublicId string `json:"public_id,omitempty" gorm:"primary_key"`
+	// SessionId of the session this connection belongs to
+	SessionId string `json:"session_id,omitempty" gorm:"default:null"`
+	// ClientTcpAddress of the client's tcp connection
+	ClientAddress string `json:"client_address,omitempty" gorm:"column:client_tcp_address;default:null"`
+	// ClientTcpPort of the client's tcp connection
+	ClientPort uint32 `json:"client_port,omitempty" gorm:"column:client_tcp_port;default:null"`
+	// BackendTcpAddress of the backend's tcp connection
+	BackendAddress string `json:"backend_address,omitempty" gorm:"column:backend_tcp_address;default:null"`
+	// BackendTcpPort of the backend's tcp connection
+	BackendPort uint32 `json:"backend_port,omitempty" gorm:"column:backend_tcp_port;default:null"`
+	// BytesUp is the number of bytes sent from the client to the backend
+	BytesUp uint64 `json:"bytes_up,omitempty" gorm:"default:0"`
+	// BytesDown is the number of bytes sent from the backend to the client
+	BytesDown uint64 `json:"bytes_down,omitempty" gorm:"default:0"`
+	// PacketsUp is the number of packets sent from the client to the backend
+	PacketsUp uint64 `json:"packets_up,omitempty" gorm:"default:0"`
+	// PacketsDown is the number of packets sent from the backend to the client
+	PacketsDown uint64 `json:"packets_down,omitempty" gorm:"default:0"`
+	// ClosedAt is set once the connection has been torn down; it's nil for
+	// in-flight connections
+	ClosedAt *timestamp.Timestamp `json:"closed_at,omitempty" gorm:"default:null"`
+	// CloseReason records why the connection was torn down. It's only
+	// meaningful once ClosedAt is set
+	CloseReason CloseReason `json:"close_reason,omitempty" gorm:"default:null"`
+	// CreateTime from the RDBMS
+	CreateTime *timestamp.Timestamp `json:"create_time,omitempty" gorm:"default:current_timestamp"`
+	// UpdateTime from the RDBMS
+	UpdateTime *timestamp.Timestamp `json:"update_time,omitempty" gorm:"default:current_timestamp"`
+
+	tableName string `gorm:"-"`
+}
+
+// NewConnection creates a new in-memory connection.  This is the only way
+// to create a Connection for the initial create of the connection.
+// ClientAddress and BackendAddress must be IPv4 or IPv6 literals (bare,
+// bracketed, with a zone id, or with a port); by default a hostname is
+// rejected for both. Pass WithAllowHostname(true) to let BackendAddress be
+// a DNS name as well -- ClientAddress always requires a literal, since it
+// comes from an accepted TCP connection rather than operator config.
+func NewConnection(sessionId, clientAddress string, clientPort uint32, backendAddress string, backendPort uint32, opt ...Option) (*Connection, error) {
+	opts := getOpts(opt...)
+
+	if sessionId == "" {
+		return nil, db.ErrInvalidParameter
+	}
+	if clientPort == 0 {
+		return nil, db.ErrInvalidParameter
+	}
+	if backendPort == 0 {
+		return nil, db.ErrInvalidParameter
+	}
+
+	client, err := parseAddress(clientAddress, false)
+	if err != nil {
+		return nil, err
+	}
+	backend, err := parseAddress(backendAddress, opts.withAllowHostname)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Connection{
+		SessionId:      sessionId,
+		ClientAddress:  client,
+		ClientPort:     clientPort,
+		BackendAddress: backend,
+		BackendPort:    backendPort,
+	}, nil
+}
+
+// AllocConnection will allocate a Connection
+func AllocConnection() Connection {
+	return Connection{}
+}
+
+// Clone creates a clone of the Connection
+func (c *Connection) Clone() interface{} {
+	clone := &Connection{
+		PublicId:       c.PublicId,
+		SessionId:      c.SessionId,
+		ClientAddress:  c.ClientAddress,
+		ClientPort:     c.ClientPort,
+		BackendAddress: c.BackendAddress,
+		BackendPort:    c.BackendPort,
+		BytesUp:        c.BytesUp,
+		BytesDown:      c.BytesDown,
+		PacketsUp:      c.PacketsUp,
+		PacketsDown:    c.PacketsDown,
+		CloseReason:    c.CloseReason,
+	}
+	if c.ClosedAt != nil {
+		clone.ClosedAt = &timestamp.Timestamp{Timestamp: c.ClosedAt.Timestamp}
+	}
+	if c.CreateTime != nil {
+		clone.CreateTime = &timestamp.Timestamp{Timestamp: c.CreateTime.Timestamp}
+	}
+	if c.UpdateTime != nil {
+		clone.UpdateTime = &timestamp.Timestamp{Timestamp: c.UpdateTime.Timestamp}
+	}
+	return clone
+}
+
+// TableName returns the tablename to override the default gorm table name
+func (c *Connection) TableName() string {
+	if c.tableName != "" {
+		return c.tableName
+	}
+	return defaultConnectionTableName
+}
+
+// SetTableName sets the tablename and satisfies the ReplayableMessage
+// interface. If the caller passes an empty string, the name will be reset
+// to the default name.
+func (c *Connection) SetTableName(n string) {
+	c.tableName = n
+}
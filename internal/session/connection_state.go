@@ -0,0 +1,67 @@
+package session
+
+import (
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+)
+
+const defaultConnectionStateTableName = "session_connection_state"
+
+// ConnectionState is an enum of the lifecycle states a Connection moves
+// through, from being authorized by the controller, to the worker actually
+// establishing it, through teardown.
+type ConnectionState string
+
+const (
+	ConnectionAuthorized ConnectionState = "authorized"
+	ConnectionConnected  ConnectionState = "connected"
+	ConnectionClosing    ConnectionState = "closing"
+	ConnectionClosed     ConnectionState = "closed"
+)
+
+// connectionStateTransitions enumerates the legal forward moves in the
+// connection lifecycle. It's checked by the repository before a state
+// change is written; the database additionally enforces the invariant with
+// a trigger so the repository check is a defense-in-depth measure, not the
+// only one.
+var connectionStateTransitions = map[ConnectionState]ConnectionState{
+	"":                   ConnectionAuthorized,
+	ConnectionAuthorized: ConnectionConnected,
+	ConnectionConnected:  ConnectionClosing,
+	ConnectionClosing:    ConnectionClosed,
+}
+
+// ConnectionStateRecord is one row of a connection's state history. Exactly
+// one record for a given connection has a nil EndTime at any point in
+// time: the record for its current state.
+type ConnectionStateRecord struct {
+	// ConnectionId of the connection this state belongs to
+	ConnectionId string `json:"connection_id,omitempty" gorm:"primary_key"`
+	// State of the connection for this record
+	State ConnectionState `json:"state,omitempty" gorm:"primary_key"`
+	// StartTime of this state
+	StartTime *timestamp.Timestamp `json:"start_time,omitempty" gorm:"primary_key;default:current_timestamp"`
+	// EndTime of this state; nil while the connection is in this state
+	EndTime *timestamp.Timestamp `json:"end_time,omitempty" gorm:"default:null"`
+
+	tableName string `gorm:"-"`
+}
+
+// AllocConnectionStateRecord will allocate a ConnectionStateRecord
+func AllocConnectionStateRecord() ConnectionStateRecord {
+	return ConnectionStateRecord{}
+}
+
+// TableName returns the tablename to override the default gorm table name
+func (s *ConnectionStateRecord) TableName() string {
+	if s.tableName != "" {
+		return s.tableName
+	}
+	return defaultConnectionStateTableName
+}
+
+// SetTableName sets the tablename and satisfies the ReplayableMessage
+// interface. If the caller passes an empty string, the name will be reset
+// to the default name.
+func (s *ConnectionStateRecord) SetTableName(n string) {
+	s.tableName = n
+}
@@ -1,14 +1,19 @@
 package session
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestConnection_Create(t *testing.T) {
@@ -24,6 +29,7 @@ func TestConnection_Create(t *testing.T) {
 		clientPort     uint32
 		backendAddress string
 		backendPort    uint32
+		allowHostname  bool
 	}
 	tests := []struct {
 		name          string
@@ -78,7 +84,7 @@ func TestConnection_Create(t *testing.T) {
 			name: "empty-client-port",
 			args: args{
 				sessionId:      s.PublicId,
-				clientAddress:  "localhost",
+				clientAddress:  "127.0.0.1",
 				backendAddress: "127.0.0.1",
 				backendPort:    2222,
 			},
@@ -89,7 +95,7 @@ func TestConnection_Create(t *testing.T) {
 			name: "empty-backend-address",
 			args: args{
 				sessionId:     s.PublicId,
-				clientAddress: "localhost",
+				clientAddress: "127.0.0.1",
 				clientPort:    22,
 				backendPort:   2222,
 			},
@@ -100,9 +106,101 @@ func TestConnection_Create(t *testing.T) {
 			name: "empty-backend-port",
 			args: args{
 				sessionId:      s.PublicId,
-				clientAddress:  "localhost",
+				clientAddress:  "127.0.0.1",
+				clientPort:     22,
+				backendAddress: "127.0.0.1",
+			},
+			wantErr:   true,
+			wantIsErr: db.ErrInvalidParameter,
+		},
+		{
+			name: "client-ipv6-bare",
+			args: args{
+				sessionId:      s.PublicId,
+				clientAddress:  "::1",
+				clientPort:     22,
+				backendAddress: "127.0.0.1",
+				backendPort:    2222,
+			},
+			want: &Connection{
+				SessionId:      s.PublicId,
+				ClientAddress:  "::1",
+				ClientPort:     22,
+				BackendAddress: "127.0.0.1",
+				BackendPort:    2222,
+			},
+			create: true,
+		},
+		{
+			name: "backend-ipv6-bracketed-with-port",
+			args: args{
+				sessionId:      s.PublicId,
+				clientAddress:  "127.0.0.1",
+				clientPort:     22,
+				backendAddress: "[::1]:22",
+				backendPort:    2222,
+			},
+			want: &Connection{
+				SessionId:      s.PublicId,
+				ClientAddress:  "127.0.0.1",
+				ClientPort:     22,
+				BackendAddress: "::1",
+				BackendPort:    2222,
+			},
+			create: true,
+		},
+		{
+			name: "client-malformed-literal",
+			args: args{
+				sessionId:      s.PublicId,
+				clientAddress:  "not-an-ip-and-hostname-not-allowed!",
 				clientPort:     22,
 				backendAddress: "127.0.0.1",
+				backendPort:    2222,
+			},
+			wantErr:   true,
+			wantIsErr: db.ErrInvalidParameter,
+		},
+		{
+			name: "backend-hostname-rejected-by-default",
+			args: args{
+				sessionId:      s.PublicId,
+				clientAddress:  "127.0.0.1",
+				clientPort:     22,
+				backendAddress: "backend.example.com",
+				backendPort:    2222,
+			},
+			wantErr:   true,
+			wantIsErr: db.ErrInvalidParameter,
+		},
+		{
+			name: "backend-hostname-allowed-with-option",
+			args: args{
+				sessionId:      s.PublicId,
+				clientAddress:  "127.0.0.1",
+				clientPort:     22,
+				backendAddress: "backend.example.com",
+				backendPort:    2222,
+				allowHostname:  true,
+			},
+			want: &Connection{
+				SessionId:      s.PublicId,
+				ClientAddress:  "127.0.0.1",
+				ClientPort:     22,
+				BackendAddress: "backend.example.com",
+				BackendPort:    2222,
+			},
+			create: true,
+		},
+		{
+			name: "client-hostname-rejected-even-with-option",
+			args: args{
+				sessionId:      s.PublicId,
+				clientAddress:  "client.example.com",
+				clientPort:     22,
+				backendAddress: "127.0.0.1",
+				backendPort:    2222,
+				allowHostname:  true,
 			},
 			wantErr:   true,
 			wantIsErr: db.ErrInvalidParameter,
@@ -117,6 +215,7 @@ func TestConnection_Create(t *testing.T) {
 				tt.args.clientPort,
 				tt.args.backendAddress,
 				tt.args.backendPort,
+				WithAllowHostname(tt.args.allowHostname),
 			)
 			if tt.wantErr {
 				require.Error(err)
@@ -253,3 +352,246 @@ func TestConnection_SetTableName(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_CloseConnection(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		stats     *ConnectionStats
+		reason    CloseReason
+		wantErr   bool
+		wantIsErr error
+	}{
+		{
+			name:   "valid",
+			stats:  &ConnectionStats{BytesUp: 100, BytesDown: 200, PacketsUp: 3, PacketsDown: 4},
+			reason: ClientClosed,
+		},
+		{
+			name:      "nil-stats",
+			stats:     nil,
+			reason:    ClientClosed,
+			wantErr:   true,
+			wantIsErr: db.ErrInvalidParameter,
+		},
+		{
+			name:      "unknown-reason",
+			stats:     &ConnectionStats{},
+			reason:    UnknownReason,
+			wantErr:   true,
+			wantIsErr: db.ErrInvalidParameter,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			s := TestDefaultSession(t, conn, wrapper, iamRepo)
+			c := TestConnection(t, conn, s.PublicId, "127.0.0.1", 22, "127.0.0.1", 2222, WithTestConnectionState(ConnectionConnected))
+
+			got, err := repo.CloseConnection(context.Background(), c.PublicId, tt.stats, tt.reason)
+			if tt.wantErr {
+				require.Error(err)
+				assert.True(errors.Is(err, tt.wantIsErr))
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tt.stats.BytesUp, got.BytesUp)
+			assert.Equal(tt.stats.BytesDown, got.BytesDown)
+			assert.Equal(tt.stats.PacketsUp, got.PacketsUp)
+			assert.Equal(tt.stats.PacketsDown, got.PacketsDown)
+			assert.Equal(tt.reason, got.CloseReason)
+			assert.NotNil(got.ClosedAt)
+
+			// closed is terminal: re-closing an already-closed connection is
+			// rejected by the state transition check before it ever gets to
+			// the stats write.
+			_, err = repo.CloseConnection(context.Background(), c.PublicId, tt.stats, tt.reason)
+			require.Error(err)
+			assert.True(errors.Is(err, db.ErrInvalidParameter))
+		})
+	}
+}
+
+func TestRepository_ReportConnectionStats(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw)
+	require.NoError(t, err)
+
+	s := TestDefaultSession(t, conn, wrapper, iamRepo)
+	c := TestConnection(t, conn, s.PublicId, "127.0.0.1", 22, "127.0.0.1", 2222)
+
+	got, err := repo.ReportConnectionStats(context.Background(), c.PublicId, &ConnectionStats{BytesUp: 10, BytesDown: 20, PacketsUp: 1, PacketsDown: 2})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), got.BytesUp)
+	assert.Equal(t, uint64(20), got.BytesDown)
+	assert.Nil(t, got.ClosedAt)
+	assert.Empty(t, got.CloseReason)
+}
+
+func TestRepository_ConnectionStateTransitions(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw)
+	require.NoError(t, err)
+
+	newAuthorizedConnection := func(t *testing.T) *Connection {
+		t.Helper()
+		s := TestDefaultSession(t, conn, wrapper, iamRepo)
+		return TestConnection(t, conn, s.PublicId, "127.0.0.1", 22, "127.0.0.1", 2222)
+	}
+
+	t.Run("authorized-to-connected-to-closing-to-closed", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		c := newAuthorizedConnection(t)
+
+		_, err := repo.ConnectConnection(context.Background(), c.PublicId)
+		require.NoError(err)
+
+		_, err = repo.CloseConnection(context.Background(), c.PublicId, &ConnectionStats{}, ClientClosed)
+		require.NoError(err)
+
+		// closed is terminal: trying to connect again is illegal.
+		_, err = repo.ConnectConnection(context.Background(), c.PublicId)
+		assert.Error(err)
+		assert.True(errors.Is(err, db.ErrInvalidParameter))
+	})
+
+	t.Run("double-authorize-is-illegal", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		c := newAuthorizedConnection(t)
+		_, err := repo.AuthorizeConnection(context.Background(), c.PublicId)
+		assert.Error(err)
+		assert.True(errors.Is(err, db.ErrInvalidParameter))
+	})
+
+	t.Run("close-before-connect-is-illegal", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		c := newAuthorizedConnection(t)
+		_, err := repo.CloseConnection(context.Background(), c.PublicId, &ConnectionStats{}, ClientClosed)
+		assert.Error(err)
+		assert.True(errors.Is(err, db.ErrInvalidParameter))
+	})
+
+	t.Run("unknown-connection-id", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		id, err := db.NewPublicId(ConnectionPrefix)
+		require.NoError(err)
+		_, err = repo.ConnectConnection(context.Background(), id)
+		assert.Error(err)
+	})
+}
+
+// recordingEventSink is a test-only EventSink that records the type of
+// every event it receives, in order.
+type recordingEventSink struct {
+	events []string
+}
+
+func (r *recordingEventSink) OnConnectionCreated(context.Context, *Connection) {
+	r.events = append(r.events, "created")
+}
+
+func (r *recordingEventSink) OnConnectionClosed(context.Context, *Connection) {
+	r.events = append(r.events, "closed")
+}
+
+func (r *recordingEventSink) OnConnectionStats(context.Context, *Connection) {
+	r.events = append(r.events, "stats")
+}
+
+func TestRepository_EventSink(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+
+	t.Run("emits created, stats, and closed", func(t *testing.T) {
+		require := require.New(t)
+		sink := &recordingEventSink{}
+		s := TestDefaultSession(t, conn, wrapper, iamRepo)
+		c := TestConnection(t, conn, s.PublicId, "127.0.0.1", 22, "127.0.0.1", 2222, WithEventSink(sink))
+		require.Equal([]string{"created"}, sink.events)
+
+		rw := db.New(conn)
+		repo, err := NewRepository(rw, rw, WithEventSink(sink))
+		require.NoError(err)
+
+		_, err = repo.ConnectConnection(context.Background(), c.PublicId)
+		require.NoError(err)
+
+		_, err = repo.ReportConnectionStats(context.Background(), c.PublicId, &ConnectionStats{BytesUp: 1})
+		require.NoError(err)
+
+		_, err = repo.CloseConnection(context.Background(), c.PublicId, &ConnectionStats{BytesUp: 2}, ClientClosed)
+		require.NoError(err)
+
+		require.Equal([]string{"created", "stats", "closed"}, sink.events)
+	})
+
+	t.Run("defaults to a no-op sink", func(t *testing.T) {
+		require := require.New(t)
+		s := TestDefaultSession(t, conn, wrapper, iamRepo)
+		// no WithEventSink option -- must not panic with a nil sink.
+		require.NotPanics(func() {
+			TestConnection(t, conn, s.PublicId, "127.0.0.1", 22, "127.0.0.1", 2222)
+		})
+	})
+}
+
+func TestJSONLinesEventSink(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	sink := NewJSONLinesEventSink(&buf)
+	closedAt := &timestamp.Timestamp{Timestamp: timestamppb.Now()}
+	c := &Connection{
+		PublicId:      "sc_test",
+		SessionId:     "s_test",
+		ClientAddress: "127.0.0.1",
+		ClientPort:    22,
+		BytesUp:       10,
+		ClosedAt:      closedAt,
+	}
+	sink.OnConnectionCreated(context.Background(), c)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 1)
+
+	var got jsonlEvent
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &got))
+	assert.Equal(t, "connection_created", got.Type)
+	assert.Equal(t, c.PublicId, got.ConnectionId)
+	assert.Equal(t, c.SessionId, got.SessionId)
+	assert.Equal(t, c.BytesUp, got.BytesUp)
+	require.NotNil(t, got.ClosedAt)
+	assert.True(t, closedAt.Timestamp.AsTime().Equal(*got.ClosedAt))
+	assert.Nil(t, got.CreateTime)
+}
+
+func TestFanOutEventSink(t *testing.T) {
+	t.Parallel()
+	a := &recordingEventSink{}
+	b := &recordingEventSink{}
+	fan := NewFanOutEventSink(a, b)
+
+	c := &Connection{PublicId: "sc_test"}
+	fan.OnConnectionCreated(context.Background(), c)
+	fan.OnConnectionStats(context.Background(), c)
+	fan.OnConnectionClosed(context.Background(), c)
+
+	assert.Equal(t, []string{"created", "stats", "closed"}, a.events)
+	assert.Equal(t, []string{"created", "stats", "closed"}, b.events)
+}
@@ -0,0 +1,59 @@
+package session
+
+import "context"
+
+// EventSink is a first-class integration point for shipping connection
+// lifecycle events to an external system (a SIEM, a metrics pipeline,
+// etc.) without the consumer having to poll the database. Repository
+// invokes the relevant method after each successful write; a sink must not
+// block the write path for long, since it runs synchronously with the
+// repository call.
+type EventSink interface {
+	// OnConnectionCreated fires once a connection has been authorized.
+	OnConnectionCreated(ctx context.Context, c *Connection)
+	// OnConnectionClosed fires once a connection's final stats and close
+	// reason have been written.
+	OnConnectionClosed(ctx context.Context, c *Connection)
+	// OnConnectionStats fires on every in-flight stats report.
+	OnConnectionStats(ctx context.Context, c *Connection)
+}
+
+// noopEventSink is the default EventSink: it discards every event. It's
+// what a Repository uses when constructed without WithEventSink.
+type noopEventSink struct{}
+
+func (noopEventSink) OnConnectionCreated(context.Context, *Connection) {}
+func (noopEventSink) OnConnectionClosed(context.Context, *Connection)  {}
+func (noopEventSink) OnConnectionStats(context.Context, *Connection)   {}
+
+// FanOutEventSink fans a single event out to every sink it wraps, in
+// order. A panic from one sink is not recovered -- sinks are expected to
+// handle their own errors internally, matching the rest of EventSink's
+// fire-and-forget contract.
+type FanOutEventSink struct {
+	sinks []EventSink
+}
+
+// NewFanOutEventSink returns an EventSink that fans every event out to
+// each of the given sinks.
+func NewFanOutEventSink(sinks ...EventSink) *FanOutEventSink {
+	return &FanOutEventSink{sinks: sinks}
+}
+
+func (f *FanOutEventSink) OnConnectionCreated(ctx context.Context, c *Connection) {
+	for _, s := range f.sinks {
+		s.OnConnectionCreated(ctx, c)
+	}
+}
+
+func (f *FanOutEventSink) OnConnectionClosed(ctx context.Context, c *Connection) {
+	for _, s := range f.sinks {
+		s.OnConnectionClosed(ctx, c)
+	}
+}
+
+func (f *FanOutEventSink) OnConnectionStats(ctx context.Context, c *Connection) {
+	for _, s := range f.sinks {
+		s.OnConnectionStats(ctx, c)
+	}
+}
@@ -0,0 +1,4 @@
+package session
+
+// ConnectionPrefix is the prefix for public ids of a session connection.
+const ConnectionPrefix = "sc"
@@ -0,0 +1,96 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+)
+
+// jsonlEvent is the shape written by JSONLinesEventSink, one per line.
+type jsonlEvent struct {
+	Type           string     `json:"type"`
+	SessionId      string     `json:"session_id"`
+	ConnectionId   string     `json:"connection_id"`
+	ClientAddress  string     `json:"client_address"`
+	ClientPort     uint32     `json:"client_port"`
+	BackendAddress string     `json:"backend_address"`
+	BackendPort    uint32     `json:"backend_port"`
+	BytesUp        uint64     `json:"bytes_up"`
+	BytesDown      uint64     `json:"bytes_down"`
+	PacketsUp      uint64     `json:"packets_up"`
+	PacketsDown    uint64     `json:"packets_down"`
+	CloseReason    string     `json:"close_reason,omitempty"`
+	CreateTime     *time.Time `json:"create_time,omitempty"`
+	ClosedAt       *time.Time `json:"closed_at,omitempty"`
+}
+
+// toTime converts a db timestamp to a *time.Time, returning nil if ts is
+// nil -- the connection hasn't been closed yet, for example.
+func toTime(ts *timestamp.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.Timestamp.AsTime()
+	return &t
+}
+
+// JSONLinesEventSink is an EventSink that writes one JSON object per line
+// to an underlying io.Writer, suitable for tailing into a SIEM or log
+// pipeline. Writes are serialized with a mutex since the underlying writer
+// (a file, a socket) may not be safe for concurrent use.
+type JSONLinesEventSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLinesEventSink returns an EventSink that writes newline-delimited
+// JSON events to w.
+func NewJSONLinesEventSink(w io.Writer) *JSONLinesEventSink {
+	return &JSONLinesEventSink{w: w}
+}
+
+func (s *JSONLinesEventSink) write(e jsonlEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	b = append(b, '\n')
+	_, _ = s.w.Write(b)
+}
+
+func (s *JSONLinesEventSink) toEvent(eventType string, c *Connection) jsonlEvent {
+	return jsonlEvent{
+		Type:           eventType,
+		SessionId:      c.SessionId,
+		ConnectionId:   c.PublicId,
+		ClientAddress:  c.ClientAddress,
+		ClientPort:     c.ClientPort,
+		BackendAddress: c.BackendAddress,
+		BackendPort:    c.BackendPort,
+		BytesUp:        c.BytesUp,
+		BytesDown:      c.BytesDown,
+		PacketsUp:      c.PacketsUp,
+		PacketsDown:    c.PacketsDown,
+		CloseReason:    string(c.CloseReason),
+		CreateTime:     toTime(c.CreateTime),
+		ClosedAt:       toTime(c.ClosedAt),
+	}
+}
+
+func (s *JSONLinesEventSink) OnConnectionCreated(_ context.Context, c *Connection) {
+	s.write(s.toEvent("connection_created", c))
+}
+
+func (s *JSONLinesEventSink) OnConnectionClosed(_ context.Context, c *Connection) {
+	s.write(s.toEvent("connection_closed", c))
+}
+
+func (s *JSONLinesEventSink) OnConnectionStats(_ context.Context, c *Connection) {
+	s.write(s.toEvent("connection_stats", c))
+}
@@ -0,0 +1,45 @@
+package session
+
+// getOpts - iterate the inbound Options and return a struct
+func getOpts(opt ...Option) options {
+	opts := options{}
+	for _, o := range opt {
+		o(&opts)
+	}
+	return opts
+}
+
+// Option - how Options are passed as arguments
+type Option func(*options)
+
+// options = how options are represented
+type options struct {
+	withLimit           int
+	withConnectionState ConnectionState
+	withAllowHostname   bool
+	withEventSink       EventSink
+}
+
+// WithLimit provides an option to override the default limit
+func WithLimit(limit int) Option {
+	return func(o *options) {
+		o.withLimit = limit
+	}
+}
+
+// WithAllowHostname provides an option to let NewConnection accept a DNS
+// hostname for BackendAddress instead of requiring an IP literal.
+func WithAllowHostname(allow bool) Option {
+	return func(o *options) {
+		o.withAllowHostname = allow
+	}
+}
+
+// WithEventSink provides an option to have a Repository emit connection
+// lifecycle events to sink after each successful write. Defaults to a
+// no-op sink when not provided.
+func WithEventSink(sink EventSink) Option {
+	return func(o *options) {
+		o.withEventSink = sink
+	}
+}
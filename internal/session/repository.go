@@ -0,0 +1,44 @@
+package session
+
+import (
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// Repository is the session database repository
+type Repository struct {
+	reader db.Reader
+	writer db.Writer
+
+	// defaultLimit provides a default for limiting the number of results returned from the repo
+	defaultLimit int
+
+	// eventSink receives connection lifecycle events after each successful
+	// write; defaults to a no-op sink
+	eventSink EventSink
+}
+
+// NewRepository creates a new session Repository. Supported options are
+// WithLimit and WithEventSink.
+func NewRepository(r db.Reader, w db.Writer, opt ...Option) (*Repository, error) {
+	if r == nil {
+		return nil, db.ErrInvalidParameter
+	}
+	if w == nil {
+		return nil, db.ErrInvalidParameter
+	}
+	opts := getOpts(opt...)
+	if opts.withLimit == 0 {
+		// zero signals the boundary defaults should be used.
+		opts.withLimit = db.DefaultLimit
+	}
+	sink := opts.withEventSink
+	if sink == nil {
+		sink = noopEventSink{}
+	}
+	return &Repository{
+		reader:       r,
+		writer:       w,
+		defaultLimit: opts.withLimit,
+		eventSink:    sink,
+	}, nil
+}
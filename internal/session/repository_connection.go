@@ -0,0 +1,127 @@
+package session
+
+import (
+	"context"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CloseConnection writes the final stats and close reason for a connection
+// and transitions it through closing to closed. The transition is only
+// legal from connected, matching connectionStateTransitions; the stats
+// update and the two state writes happen in the same transaction, so a
+// reader never observes a closed connection with stale or missing stats.
+func (r *Repository) CloseConnection(ctx context.Context, publicId string, stats *ConnectionStats, reason CloseReason) (*Connection, error) {
+	if publicId == "" {
+		return nil, db.ErrInvalidParameter
+	}
+	if stats == nil {
+		return nil, db.ErrInvalidParameter
+	}
+	if reason == "" || reason == UnknownReason {
+		return nil, db.ErrInvalidParameter
+	}
+
+	var conn *Connection
+	_, err := r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{}, func(reader db.Reader, w db.Writer) error {
+		for _, next := range []ConnectionState{ConnectionClosing, ConnectionClosed} {
+			if err := r.writeConnectionStateTx(ctx, reader, w, publicId, next); err != nil {
+				return err
+			}
+		}
+
+		c := AllocConnection()
+		c.PublicId = publicId
+		c.BytesUp = stats.BytesUp
+		c.BytesDown = stats.BytesDown
+		c.PacketsUp = stats.PacketsUp
+		c.PacketsDown = stats.PacketsDown
+		c.CloseReason = reason
+		c.ClosedAt = &timestamp.Timestamp{Timestamp: timestamppb.Now()}
+
+		rowsUpdated, err := w.Update(ctx, &c, []string{
+			"BytesUp", "BytesDown", "PacketsUp", "PacketsDown", "CloseReason", "ClosedAt",
+		}, nil, db.WithWhere("closed_at is null"))
+		if err != nil {
+			return err
+		}
+		if rowsUpdated == 0 {
+			return db.ErrRecordNotFound
+		}
+		conn = &c
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.eventSink.OnConnectionClosed(ctx, conn)
+	return conn, nil
+}
+
+// CreateConnection inserts a new connection and authorizes it in the same
+// transaction, then emits an OnConnectionCreated event.
+func (r *Repository) CreateConnection(ctx context.Context, c *Connection) (*Connection, error) {
+	if c == nil {
+		return nil, db.ErrInvalidParameter
+	}
+	if c.PublicId != "" {
+		return nil, db.ErrInvalidParameter
+	}
+
+	id, err := db.NewPublicId(ConnectionPrefix)
+	if err != nil {
+		return nil, err
+	}
+	c.PublicId = id
+
+	var conn *Connection
+	_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{}, func(reader db.Reader, w db.Writer) error {
+		if err := w.Create(ctx, c); err != nil {
+			return err
+		}
+		if err := r.writeConnectionStateTx(ctx, reader, w, c.PublicId, ConnectionAuthorized); err != nil {
+			return err
+		}
+		conn = c
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.eventSink.OnConnectionCreated(ctx, conn)
+	return conn, nil
+}
+
+// ReportConnectionStats updates the in-flight byte/packet counters for a
+// connection that's still open. It's called periodically by the worker
+// while a connection is active, so it intentionally does not touch
+// ClosedAt or CloseReason.
+func (r *Repository) ReportConnectionStats(ctx context.Context, publicId string, stats *ConnectionStats) (*Connection, error) {
+	if publicId == "" {
+		return nil, db.ErrInvalidParameter
+	}
+	if stats == nil {
+		return nil, db.ErrInvalidParameter
+	}
+
+	conn := AllocConnection()
+	conn.PublicId = publicId
+	conn.BytesUp = stats.BytesUp
+	conn.BytesDown = stats.BytesDown
+	conn.PacketsUp = stats.PacketsUp
+	conn.PacketsDown = stats.PacketsDown
+
+	rowsUpdated, err := r.writer.Update(ctx, &conn, []string{
+		"BytesUp", "BytesDown", "PacketsUp", "PacketsDown",
+	}, nil, db.WithWhere("closed_at is null"))
+	if err != nil {
+		return nil, err
+	}
+	if rowsUpdated == 0 {
+		return nil, db.ErrRecordNotFound
+	}
+	r.eventSink.OnConnectionStats(ctx, &conn)
+	return &conn, nil
+}
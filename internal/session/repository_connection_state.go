@@ -0,0 +1,90 @@
+package session
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// writeConnectionStateTx validates and writes a single state transition
+// using the given reader/writer, without opening its own transaction. It's
+// the shared core every transition goes through -- whether driven
+// standalone by transitionConnectionState or nested inside a larger
+// transaction such as CreateConnection's -- so the legality check and the
+// insert can never drift between call sites.
+func (r *Repository) writeConnectionStateTx(ctx context.Context, reader db.Reader, w db.Writer, connectionId string, next ConnectionState) error {
+	current := AllocConnectionStateRecord()
+	err := reader.LookupWhere(ctx, &current, "connection_id = ? and end_time is null", []interface{}{connectionId})
+	var from ConnectionState
+	switch {
+	case err == nil:
+		from = current.State
+	case errors.Is(err, db.ErrRecordNotFound):
+		from = ""
+	default:
+		return err
+	}
+
+	if want, ok := connectionStateTransitions[from]; !ok || want != next {
+		return db.ErrInvalidParameter
+	}
+
+	rec := AllocConnectionStateRecord()
+	rec.ConnectionId = connectionId
+	rec.State = next
+	return w.Create(ctx, &rec)
+}
+
+// transitionConnectionState moves a connection's current state to next. It
+// rejects the move if it's not present in connectionStateTransitions; the
+// session_connection_state table also has a trigger enforcing the same
+// invariant so an illegal transition can never land even if this check is
+// ever bypassed. The previous state record, if any, is closed out by a DB
+// trigger on insert rather than by this method, so the two writes can
+// never race against a concurrent transition.
+func (r *Repository) transitionConnectionState(ctx context.Context, connectionId string, next ConnectionState) (*Connection, error) {
+	if connectionId == "" {
+		return nil, db.ErrInvalidParameter
+	}
+
+	var conn *Connection
+	_, err := r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{}, func(reader db.Reader, w db.Writer) error {
+		if err := r.writeConnectionStateTx(ctx, reader, w, connectionId, next); err != nil {
+			return err
+		}
+
+		c := AllocConnection()
+		c.PublicId = connectionId
+		if err := reader.LookupById(ctx, &c); err != nil {
+			return err
+		}
+		conn = &c
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// AuthorizeConnection records that a connection has been authorized by the
+// controller. It's the first state in a connection's lifecycle.
+func (r *Repository) AuthorizeConnection(ctx context.Context, connectionId string) (*Connection, error) {
+	return r.transitionConnectionState(ctx, connectionId, ConnectionAuthorized)
+}
+
+// ConnectConnection records that a worker has actually established a
+// connection that was previously authorized.
+func (r *Repository) ConnectConnection(ctx context.Context, connectionId string) (*Connection, error) {
+	return r.transitionConnectionState(ctx, connectionId, ConnectionConnected)
+}
+
+// MarkConnectionClosing records that a connection's teardown has begun,
+// without yet writing its final stats or close reason. It's the same
+// transition CloseConnection moves through on its way to closed, exposed
+// on its own so callers -- and tests -- can observe a connection sitting
+// in the closing state.
+func (r *Repository) MarkConnectionClosing(ctx context.Context, connectionId string) (*Connection, error) {
+	return r.transitionConnectionState(ctx, connectionId, ConnectionClosing)
+}
@@ -0,0 +1,42 @@
+package session
+
+import (
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+)
+
+const defaultSessionTableName = "session"
+
+// Session is a recorded dynamic, time-limited relationship between a user,
+// a target, and one or more connections.
+type Session struct {
+	// PublicId is a surrogate key suitable for use in a public API
+	PublicId string `json:"public_id,omitempty" gorm:"primary_key"`
+	// UserId of the user for this session
+	UserId string `json:"user_id,omitempty" gorm:"default:null"`
+	// CreateTime from the RDBMS
+	CreateTime *timestamp.Timestamp `json:"create_time,omitempty" gorm:"default:current_timestamp"`
+	// UpdateTime from the RDBMS
+	UpdateTime *timestamp.Timestamp `json:"update_time,omitempty" gorm:"default:current_timestamp"`
+
+	tableName string `gorm:"-"`
+}
+
+// AllocSession will allocate a Session
+func AllocSession() Session {
+	return Session{}
+}
+
+// TableName returns the tablename to override the default gorm table name
+func (s *Session) TableName() string {
+	if s.tableName != "" {
+		return s.tableName
+	}
+	return defaultSessionTableName
+}
+
+// SetTableName sets the tablename and satisfies the ReplayableMessage
+// interface. If the caller passes an empty string, the name will be reset
+// to the default name.
+func (s *Session) SetTableName(n string) {
+	s.tableName = n
+}
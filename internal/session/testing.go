@@ -0,0 +1,83 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	wrapping "github.com/hashicorp/go-kms-wrapping/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDefaultSession creates a session in the repository for use by other
+// tests in this package. The session's user is created in a freshly
+// created test org via the iam repository.
+func TestDefaultSession(t *testing.T, conn *db.DB, wrapper wrapping.Wrapper, iamRepo *iam.Repository) *Session {
+	t.Helper()
+	require := require.New(t)
+	rw := db.New(conn)
+
+	org, _ := iam.TestScopes(t, iamRepo)
+	user := iam.TestUser(t, iamRepo, org.GetPublicId())
+
+	s := AllocSession()
+	id, err := db.NewPublicId("s")
+	require.NoError(err)
+	s.PublicId = id
+	s.UserId = user.GetPublicId()
+
+	err = rw.Create(context.Background(), &s)
+	require.NoError(err)
+	return &s
+}
+
+// WithTestConnectionState drives a newly created test connection forward
+// to the given state (authorized, connected, closing, or closed) before
+// returning it. Defaults to authorized, which is the state every real
+// connection starts in.
+func WithTestConnectionState(s ConnectionState) Option {
+	return func(o *options) {
+		o.withConnectionState = s
+	}
+}
+
+// TestConnection creates a connection in the repository for use by other
+// tests in this package. It's always authorized as part of creation; pass
+// WithTestConnectionState to additionally drive it to connected, closing,
+// or closed, and WithEventSink to assert on the events emitted along the
+// way.
+func TestConnection(t *testing.T, conn *db.DB, sessionId, clientAddress string, clientPort uint32, backendAddress string, backendPort uint32, opt ...Option) *Connection {
+	t.Helper()
+	require := require.New(t)
+	rw := db.New(conn)
+	opts := getOpts(opt...)
+
+	c, err := NewConnection(sessionId, clientAddress, clientPort, backendAddress, backendPort)
+	require.NoError(err)
+
+	repo, err := NewRepository(rw, rw, opt...)
+	require.NoError(err)
+
+	c, err = repo.CreateConnection(context.Background(), c)
+	require.NoError(err)
+
+	switch opts.withConnectionState {
+	case "", ConnectionAuthorized:
+		return c
+	case ConnectionConnected:
+		_, err = repo.ConnectConnection(context.Background(), c.PublicId)
+		require.NoError(err)
+	case ConnectionClosing:
+		_, err = repo.ConnectConnection(context.Background(), c.PublicId)
+		require.NoError(err)
+		_, err = repo.MarkConnectionClosing(context.Background(), c.PublicId)
+		require.NoError(err)
+	case ConnectionClosed:
+		_, err = repo.ConnectConnection(context.Background(), c.PublicId)
+		require.NoError(err)
+		_, err = repo.CloseConnection(context.Background(), c.PublicId, &ConnectionStats{}, ClientClosed)
+		require.NoError(err)
+	}
+	return c
+}